@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"time"
+
+	"github.com/nats-io/nkeys"
+)
+
+// Account is the JWT body of an AccountClaims.
+type Account struct {
+	Imports Imports `json:"imports,omitempty"`
+	Exports Exports `json:"exports,omitempty"`
+	// Revocations is the account-wide revocation list, checked in addition to
+	// any revocations held by the individual Export a user JWT was issued
+	// against. See AccountClaims.RevokeAll/RevokeMany/IsRevoked.
+	Revocations AccountRevocationList `json:"revocations,omitempty"`
+}
+
+// Validate checks the account body for obvious problems and adds any issues found to vr.
+func (a *Account) Validate(vr *ValidationResults) {
+	a.Imports.Validate(vr)
+	a.Exports.Validate(vr)
+}
+
+// AccountClaims defines the body of an account JWT.
+type AccountClaims struct {
+	ClaimsData
+	Account `json:"nats,omitempty"`
+}
+
+// NewAccountClaims creates a new AccountClaims for the given account public key.
+func NewAccountClaims(subject string) *AccountClaims {
+	if subject == "" {
+		return nil
+	}
+	return &AccountClaims{
+		ClaimsData: ClaimsData{
+			Subject:  subject,
+			IssuedAt: now(),
+		},
+	}
+}
+
+// Claims returns the base claims data.
+func (a *AccountClaims) Claims() *ClaimsData {
+	return &a.ClaimsData
+}
+
+// Payload returns the JWT body that gets marshaled into the token.
+func (a *AccountClaims) Payload() interface{} {
+	return a
+}
+
+// Validate checks the claims for obvious problems and adds any issues found to vr.
+func (a *AccountClaims) Validate(vr *ValidationResults) {
+	a.Account.Validate(vr)
+}
+
+// RevokeAt revokes pubKey account-wide, for tokens issued at or before at.
+func (a *AccountClaims) RevokeAt(pubKey string, at time.Time) {
+	if a.Account.Revocations == nil {
+		a.Account.Revocations = AccountRevocationList{}
+	}
+	a.Account.Revocations.RevokeAt(pubKey, at)
+}
+
+// RevokeAll revokes every user JWT issued by this account with iat <= before,
+// unless a key has separately been allow-listed past before via RevokeAt.
+func (a *AccountClaims) RevokeAll(before time.Time) {
+	a.RevokeAt(revokeAllKey, before)
+}
+
+// RevokeMany revokes every key in pubKeys account-wide, at the given time.
+func (a *AccountClaims) RevokeMany(pubKeys []string, at time.Time) {
+	if a.Account.Revocations == nil {
+		a.Account.Revocations = AccountRevocationList{}
+	}
+	a.Account.Revocations.RevokeMany(pubKeys, at)
+}
+
+// ClearRevocation removes any account-wide revocation for the given public key.
+func (a *AccountClaims) ClearRevocation(pubKey string) {
+	a.Account.Revocations.ClearRevocation(pubKey)
+}
+
+// IsRevoked reports whether a token for pubKey issued at iat is revoked,
+// honoring both export's own revocation list and the account-wide one.
+// export may be nil if the caller has no specific export to check.
+func (a *AccountClaims) IsRevoked(export *Export, pubKey string, iat time.Time) bool {
+	if export != nil && export.IsRevokedAt(pubKey, iat) {
+		return true
+	}
+	return a.Account.Revocations.IsRevokedAt(pubKey, iat)
+}
+
+// Encode signs the account claims with the given key pair and returns the compact JWT.
+func (a *AccountClaims) Encode(kp nkeys.KeyPair) (string, error) {
+	pub, err := kp.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	a.Issuer = pub
+	return encode(a, kp)
+}
+
+// DecodeAccountClaims decodes and verifies an account JWT.
+func DecodeAccountClaims(token string) (*AccountClaims, error) {
+	ac := &AccountClaims{}
+	if err := decode(token, ac); err != nil {
+		return nil, err
+	}
+	return ac, nil
+}
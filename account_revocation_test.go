@@ -0,0 +1,149 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAccountRevokeAllRevokesEveryIssuedKey(t *testing.T) {
+	akp := createAccountNKey(t)
+	apk := publicKey(akp, t)
+	account := NewAccountClaims(apk)
+
+	now := time.Now()
+	account.RevokeAll(now)
+
+	if !account.IsRevoked(nil, "any-user-key", now) {
+		t.Errorf("RevokeAll should revoke keys with no specific entry")
+	}
+	if account.IsRevoked(nil, "any-user-key", now.Add(time.Second)) {
+		t.Errorf("a token issued after the RevokeAll cutoff should not be revoked")
+	}
+}
+
+func TestAccountAllowListOverridesRevokeAll(t *testing.T) {
+	akp := createAccountNKey(t)
+	apk := publicKey(akp, t)
+	account := NewAccountClaims(apk)
+
+	cutoff := time.Now()
+	account.RevokeAll(cutoff)
+	account.RevokeAt("trusted-user-key", time.Unix(0, 0))
+
+	if account.IsRevoked(nil, "trusted-user-key", cutoff) {
+		t.Errorf("a key with its own (earlier) entry should not fall back to the wildcard entry")
+	}
+	if !account.IsRevoked(nil, "other-user-key", cutoff) {
+		t.Errorf("keys without their own entry should still be revoked by RevokeAll")
+	}
+}
+
+func TestAccountRevokeManyAndExportRevocationCombine(t *testing.T) {
+	akp := createAccountNKey(t)
+	apk := publicKey(akp, t)
+	account := NewAccountClaims(apk)
+
+	e := &Export{Subject: "foo", Type: Stream}
+	account.Exports.Add(e)
+
+	now := time.Now()
+	account.RevokeMany([]string{"a", "b", "c"}, now)
+	e.RevokeAt("d", now)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if !account.IsRevoked(e, key, now) {
+			t.Errorf("%q should be revoked via either the account-wide or the export list", key)
+		}
+	}
+	if account.IsRevoked(e, "e", now) {
+		t.Errorf("unrelated key should not be revoked")
+	}
+}
+
+func TestAccountRevocationListCompactWireFormat(t *testing.T) {
+	r := AccountRevocationList{}
+	now := time.Now().Unix()
+	r.RevokeAt("a", time.Unix(now, 0))
+	r.RevokeAt("b", time.Unix(now, 0))
+	r.RevokeAt("c", time.Unix(now+1, 0))
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	var wire map[string][]string
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("expected marshaled form to be {timestamp: [keys]}, got %s: %v", data, err)
+	}
+	if len(wire) != 2 {
+		t.Fatalf("expected one entry per distinct timestamp, got %v", wire)
+	}
+
+	var decoded AccountRevocationList
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(decoded) != 3 || decoded["a"] != now || decoded["b"] != now || decoded["c"] != now+1 {
+		t.Errorf("compact round trip should preserve every key/timestamp, got %v", decoded)
+	}
+}
+
+func TestAccountRevocationListDecodesLegacyPerKeyForm(t *testing.T) {
+	legacy := []byte(`{"a":100,"b":100,"c":200}`)
+
+	var decoded AccountRevocationList
+	if err := json.Unmarshal(legacy, &decoded); err != nil {
+		t.Fatalf("legacy form should still decode: %v", err)
+	}
+
+	if decoded["a"] != 100 || decoded["b"] != 100 || decoded["c"] != 200 {
+		t.Errorf("legacy per-key revocations should round trip unchanged, got %v", decoded)
+	}
+}
+
+func TestRevocationListWireFormatIsUnchangedPlainMap(t *testing.T) {
+	r := RevocationList{}
+	r.RevokeAt("a", time.Unix(100, 0))
+	r.RevokeAt("b", time.Unix(200, 0))
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	// Export.Revocations must keep encoding as one entry per key - the
+	// timestamp-grouped compact form is only used by AccountRevocationList.
+	var plain map[string]int64
+	if err := json.Unmarshal(data, &plain); err != nil {
+		t.Fatalf("expected plain per-key JSON, got %s: %v", data, err)
+	}
+	if plain["a"] != 100 || plain["b"] != 200 {
+		t.Errorf("expected per-key timestamps preserved, got %v", plain)
+	}
+
+	var decoded RevocationList
+	if err := json.Unmarshal([]byte(`{"a":100,"b":100,"c":200}`), &decoded); err != nil {
+		t.Fatalf("legacy per-key form should decode: %v", err)
+	}
+	if decoded["a"] != 100 || decoded["b"] != 100 || decoded["c"] != 200 {
+		t.Errorf("expected legacy per-key revocations unchanged, got %v", decoded)
+	}
+}
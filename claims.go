@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nkeys"
+)
+
+// ClaimsData is the base set of fields shared by every kind of claim in this package.
+type ClaimsData struct {
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ID        string `json:"jti,omitempty"`
+}
+
+// Claims is implemented by every claim type (e.g. AccountClaims).
+type Claims interface {
+	Claims() *ClaimsData
+	Payload() interface{}
+}
+
+var header = map[string]string{"typ": "JWT", "alg": "ed25519-nkey"}
+
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(s string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// encode signs a claim with the given key pair and returns the compact JWT form.
+func encode(c Claims, kp nkeys.KeyPair) (string, error) {
+	h, err := encodeSegment(header)
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(c.Payload())
+	if err != nil {
+		return "", err
+	}
+	toSign := fmt.Sprintf("%s.%s", h, payload)
+	sig, err := kp.Sign([]byte(toSign))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", toSign, base64.RawURLEncoding.EncodeToString(sig)), nil
+}
+
+// decode verifies the signature and unmarshals the payload of a compact JWT into v.
+// v must embed ClaimsData (via an "iss" field) so the signing key can be recovered.
+func decode(token string, v interface{ Claims() *ClaimsData }) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jwt: expected 3 segments, got %d", len(parts))
+	}
+	if err := decodeSegment(parts[1], v); err != nil {
+		return err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("error decoding signature: %v", err)
+	}
+	kp, err := nkeys.FromPublicKey(v.Claims().Issuer)
+	if err != nil {
+		return fmt.Errorf("error parsing issuer: %v", err)
+	}
+	if err := kp.Verify([]byte(fmt.Sprintf("%s.%s", parts[0], parts[1])), sig); err != nil {
+		return fmt.Errorf("claim failed signature verification: %v", err)
+	}
+	return nil
+}
+
+func now() int64 {
+	return time.Now().Unix()
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import "testing"
+
+func TestStreamOverlapHasStructuredFieldsAndCode(t *testing.T) {
+	a := &Export{Subject: "bar.foo", Type: Stream}
+	b := &Export{Subject: "bar.*", Type: Stream}
+
+	exports := &Exports{}
+	exports.Add(a, b)
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	issues := vr.Filter(ErrExportOverlapStream)
+	if len(issues) != 1 {
+		t.Fatalf("expected one ERR_EXPORT_OVERLAP_STREAM issue, got %d", len(issues))
+	}
+
+	issue := issues[0]
+	if issue.Kind != Stream {
+		t.Errorf("expected Kind Stream, got %v", issue.Kind)
+	}
+	if issue.Subject != "bar.foo" || issue.ConflictsWith != "bar.*" {
+		t.Errorf("expected Subject/ConflictsWith to identify the overlapping pair, got %q/%q", issue.Subject, issue.ConflictsWith)
+	}
+}
+
+func TestServiceOverlapUsesServiceCode(t *testing.T) {
+	a := &Export{Subject: "bar", Type: Service}
+	b := &Export{Subject: "bar", Type: Service}
+
+	exports := &Exports{}
+	exports.Add(a, b)
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	if len(vr.Filter(ErrExportOverlapService)) != 1 {
+		t.Errorf("expected one ERR_EXPORT_OVERLAP_SERVICE issue, got %v", vr.Issues)
+	}
+	if len(vr.Filter(ErrExportOverlapStream)) != 0 {
+		t.Errorf("service-only overlap should not carry the stream error code")
+	}
+}
+
+func TestUnknownTypeOverlapStillFlagged(t *testing.T) {
+	a := &Export{Subject: "bar", Type: Unknown}
+	b := &Export{Subject: "bar", Type: Unknown}
+
+	exports := &Exports{}
+	exports.Add(a, b)
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	// Each export already gets its own CategoryUnknownType issue; on top of
+	// that, two Unknown-type exports on the same subject must still get an
+	// overlap diagnostic, matching the pre-two-pass behavior.
+	if len(vr.IssuesByAction(Deny)) != 3 {
+		t.Errorf("expected 2 unknown-type issues + 1 overlap issue, got %d (%v)", len(vr.IssuesByAction(Deny)), vr.Issues)
+	}
+
+	overlapIssues := vr.Filter(ErrExportOverlapUnknown)
+	if len(overlapIssues) != 1 {
+		t.Fatalf("expected one ERR_EXPORT_OVERLAP_UNKNOWN issue, got %d", len(overlapIssues))
+	}
+	if overlapIssues[0].Kind != Unknown {
+		t.Errorf("expected Kind Unknown, got %v", overlapIssues[0].Kind)
+	}
+	if len(vr.Filter(ErrExportOverlapService)) != 0 {
+		t.Errorf("unknown-type overlap must not be tagged with the service code")
+	}
+}
+
+func TestFilterReturnsEmptyForUnmatchedCode(t *testing.T) {
+	vr := CreateValidationResults()
+	vr.AddError("something unrelated")
+
+	if issues := vr.Filter(ErrExportOverlapStream); len(issues) != 0 {
+		t.Errorf("expected no matches, got %d", len(issues))
+	}
+}
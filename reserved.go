@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+var reservedSubjects = map[ExportType][]Subject{}
+
+func init() {
+	// These overlap with the system/jetstream/kv/object-store APIs that
+	// nats-server reserves for itself; an export claiming them would let an
+	// account hijack traffic that was never meant to leave the system account.
+	// Some of these (e.g. $JS.API.>) are reached via request/reply and so are
+	// exported as Service rather than Stream in practice, so both kinds are reserved.
+	RegisterReservedSubjects(Stream, "$SYS.>", "$JS.API.>", "$KV.>", "$OBJ.>")
+	RegisterReservedSubjects(Service, "$SYS.>", "$JS.API.>", "$KV.>", "$OBJ.>")
+}
+
+// RegisterReservedSubjects adds patterns to the set of subjects that
+// Exports.Validate refuses to let an export of the given kind overlap with.
+// Call this during program init to extend the default set (e.g. nats-server
+// registering additional internal APIs it owns).
+func RegisterReservedSubjects(kind ExportType, patterns ...Subject) {
+	reservedSubjects[kind] = append(reservedSubjects[kind], patterns...)
+}
+
+// reservedOverlap returns the reserved pattern that subj overlaps with for the
+// given export kind, or "" if there is none.
+func reservedOverlap(kind ExportType, subj Subject) Subject {
+	for _, reserved := range reservedSubjects[kind] {
+		if subj.Contains(reserved) || reserved.Contains(subj) {
+			return reserved
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import "testing"
+
+func TestExportOverlappingReservedNamespaceIsBlocking(t *testing.T) {
+	e := &Export{Subject: "$SYS.ACCOUNT.>", Type: Stream}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if !vr.IsBlocking(true) {
+		t.Errorf("export overlapping $SYS.> should be blocking")
+	}
+	if len(vr.IssuesByAction(Deny)) != 1 {
+		t.Errorf("expected one Deny issue, got %d", len(vr.IssuesByAction(Deny)))
+	}
+}
+
+func TestExportAllowReservedDowngradesToWarning(t *testing.T) {
+	e := &Export{Subject: "$SYS.ACCOUNT.>", Type: Stream, AllowReserved: true}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if vr.IsBlocking(true) {
+		t.Errorf("AllowReserved export should not block")
+	}
+	if len(vr.IssuesByAction(Warn)) != 1 {
+		t.Errorf("expected one Warn issue, got %d", len(vr.IssuesByAction(Warn)))
+	}
+}
+
+func TestExportOutsideReservedNamespaceOK(t *testing.T) {
+	e := &Export{Subject: "foo.bar", Type: Stream}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if !vr.IsEmpty() {
+		t.Errorf("unrelated subject should validate cleanly, got %v", vr.Issues)
+	}
+}
+
+func TestServiceExportOverlappingReservedNamespaceIsBlocking(t *testing.T) {
+	// $JS.API.> is reached via request/reply in practice, i.e. exported as
+	// Service rather than Stream, so it must be reserved for both kinds.
+	e := &Export{Subject: "$JS.API.CONSUMER.INFO", Type: Service}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if !vr.IsBlocking(true) {
+		t.Errorf("service export overlapping $JS.API.> should be blocking")
+	}
+	if len(vr.IssuesByAction(Deny)) != 1 {
+		t.Errorf("expected one Deny issue, got %d", len(vr.IssuesByAction(Deny)))
+	}
+}
+
+func TestRegisterReservedSubjectsExtendsDefaults(t *testing.T) {
+	original := append([]Subject(nil), reservedSubjects[Service]...)
+	RegisterReservedSubjects(Service, "$TEST.RESERVED.>")
+	defer func() { reservedSubjects[Service] = original }()
+
+	e := &Export{Subject: "$TEST.RESERVED.foo", Type: Service}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if !vr.IsBlocking(true) {
+		t.Errorf("service export overlapping a registered reserved namespace should block")
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// revokeAllKey is the map key used by RevokeAll to mean "every public key
+// without its own, more specific entry".
+const revokeAllKey = "*"
+
+// revokeAt mutates m so pubKey is revoked at or before the given time. If the
+// key was already revoked at a later time, this is a no-op, since revocation
+// windows can only be extended, never narrowed.
+func revokeAt(m map[string]int64, pubKey string, at time.Time) {
+	if m == nil {
+		return
+	}
+	newTS := at.Unix()
+	if existing, ok := m[pubKey]; ok && existing >= newTS {
+		return
+	}
+	m[pubKey] = newTS
+}
+
+// isRevokedAt checks if pubKey is revoked for a token issued at the given
+// time. An explicit entry for pubKey always takes precedence over the "*"
+// entry, so a key can be allow-listed past a RevokeAll cutoff by giving it
+// its own (earlier) timestamp.
+func isRevokedAt(m map[string]int64, pubKey string, at time.Time) bool {
+	if m == nil {
+		return false
+	}
+	if ts, ok := m[pubKey]; ok {
+		return at.Unix() <= ts
+	}
+	if ts, ok := m[revokeAllKey]; ok {
+		return at.Unix() <= ts
+	}
+	return false
+}
+
+// RevocationList is a map of public keys to unix timestamps, used for the
+// per-export Export.Revocations list. A key is considered revoked at time t
+// if t.Unix() <= the stored timestamp. It encodes as plain JSON
+// ({"pubkey": ts, ...}); see AccountRevocationList for the compact,
+// timestamp-deduplicated form used by the account-wide list.
+type RevocationList map[string]int64
+
+// RevokeAt records that the given public key is revoked at or before the
+// given time.
+func (r RevocationList) RevokeAt(pubKey string, at time.Time) {
+	revokeAt(r, pubKey, at)
+}
+
+// IsRevokedAt checks if the public key is revoked for a token issued at the given time.
+func (r RevocationList) IsRevokedAt(pubKey string, at time.Time) bool {
+	return isRevokedAt(r, pubKey, at)
+}
+
+// IsRevoked checks if the public key is revoked at the current time.
+func (r RevocationList) IsRevoked(pubKey string) bool {
+	return r.IsRevokedAt(pubKey, time.Now())
+}
+
+// ClearRevocation removes any revocation for the given public key.
+func (r RevocationList) ClearRevocation(pubKey string) {
+	if r == nil {
+		return
+	}
+	delete(r, pubKey)
+}
+
+// AccountRevocationList is the account-wide counterpart to RevocationList
+// (see AccountClaims.Revocations): same revoke/lookup semantics, including
+// the "*" wildcard, but RevokeAll/RevokeMany are expected to revoke
+// thousands of keys at once, so it encodes as a compact, timestamp-deduplicated
+// wire form instead of repeating the timestamp once per key.
+type AccountRevocationList map[string]int64
+
+// RevokeAt records that the given public key is revoked at or before the given time.
+func (r AccountRevocationList) RevokeAt(pubKey string, at time.Time) {
+	revokeAt(r, pubKey, at)
+}
+
+// RevokeMany revokes every key in pubKeys at the given time. It is a
+// convenience over calling RevokeAt in a loop.
+func (r AccountRevocationList) RevokeMany(pubKeys []string, at time.Time) {
+	for _, pubKey := range pubKeys {
+		r.RevokeAt(pubKey, at)
+	}
+}
+
+// RevokeAll revokes every public key that doesn't have its own entry - i.e.
+// every currently issued JWT with iat <= before is revoked, unless that key
+// has its own entry from RevokeAt, which is then checked instead of this one
+// whether its timestamp is earlier or later than before.
+func (r AccountRevocationList) RevokeAll(before time.Time) {
+	r.RevokeAt(revokeAllKey, before)
+}
+
+// IsRevokedAt checks if the public key is revoked for a token issued at the given time.
+func (r AccountRevocationList) IsRevokedAt(pubKey string, at time.Time) bool {
+	return isRevokedAt(r, pubKey, at)
+}
+
+// IsRevoked checks if the public key is revoked at the current time.
+func (r AccountRevocationList) IsRevoked(pubKey string) bool {
+	return r.IsRevokedAt(pubKey, time.Now())
+}
+
+// ClearRevocation removes any revocation for the given public key.
+func (r AccountRevocationList) ClearRevocation(pubKey string) {
+	if r == nil {
+		return
+	}
+	delete(r, pubKey)
+}
+
+// MarshalJSON encodes the list in a compact, timestamp-deduplicated wire
+// form: {"<unix seconds>": ["key1", "key2", ...]}. RevokeAll/RevokeMany
+// commonly revoke thousands of keys at the same cutoff, and repeating that
+// timestamp once per key would otherwise dominate the size of the JWT.
+func (r AccountRevocationList) MarshalJSON() ([]byte, error) {
+	wire := make(map[string][]string, len(r))
+	for pubKey, ts := range r {
+		key := strconv.FormatInt(ts, 10)
+		wire[key] = append(wire[key], pubKey)
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON accepts both the compact timestamp->keys form produced by
+// MarshalJSON and the legacy one-entry-per-key form used by JWTs encoded
+// before this compact form was introduced, so previously-issued revocations
+// keep decoding correctly.
+func (r *AccountRevocationList) UnmarshalJSON(data []byte) error {
+	legacy := map[string]int64{}
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*r = legacy
+		return nil
+	}
+
+	var wire map[string][]string
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	out := make(AccountRevocationList, len(wire))
+	for tsStr, pubKeys := range wire {
+		ts, err := strconv.ParseInt(tsStr, 10, 64)
+		if err != nil {
+			return err
+		}
+		for _, pubKey := range pubKeys {
+			out[pubKey] = ts
+		}
+	}
+	*r = out
+	return nil
+}
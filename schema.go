@@ -0,0 +1,64 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Schema names the shape of an export's payload: a URI identifying the
+// schema, with an optional inline JSON Schema document for self-contained
+// verification when the URI alone isn't enough.
+type Schema struct {
+	URI    string          `json:"uri,omitempty"`
+	Inline json.RawMessage `json:"inline,omitempty"`
+}
+
+// Validate checks that URI (if set) is well-formed and Inline (if set) parses
+// as JSON, adding any issues found to vr.
+func (s *Schema) Validate(vr *ValidationResults) {
+	if s == nil {
+		return
+	}
+	if s.URI != "" {
+		if err := checkURI(s.URI); err != nil {
+			vr.AddError("invalid schema uri %q: %v", s.URI, err)
+		}
+	}
+	if len(s.Inline) > 0 {
+		var v interface{}
+		if err := json.Unmarshal(s.Inline, &v); err != nil {
+			vr.AddError("inline schema does not parse as JSON: %v", err)
+		}
+	}
+}
+
+// checkURI parses s as a URI and requires it to have a scheme - url.Parse on
+// its own accepts nearly any string (including plain text with no scheme at
+// all), which isn't useful for validating something meant to be a URI naming
+// a predicate type or schema.
+func checkURI(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" {
+		return fmt.Errorf("missing URI scheme")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import "testing"
+
+func TestExportSchemaValidation(t *testing.T) {
+	e := &Export{
+		Subject:       "foo",
+		Type:          Stream,
+		PredicateType: "https://example.com/predicates/order/v1",
+		Schema:        &Schema{URI: "https://example.com/schemas/order.json", Inline: []byte(`{"type":"object"}`)},
+	}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if !vr.IsEmpty() {
+		t.Errorf("well formed predicate/schema should validate cleanly, got %v", vr.Issues)
+	}
+}
+
+func TestExportInvalidInlineSchema(t *testing.T) {
+	e := &Export{Subject: "foo", Type: Stream, Schema: &Schema{Inline: []byte(`not json`)}}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if vr.IsEmpty() {
+		t.Errorf("malformed inline schema should not validate cleanly")
+	}
+}
+
+func TestExportInvalidPredicateTypeIsRejected(t *testing.T) {
+	e := &Export{Subject: "foo", Type: Stream, PredicateType: "this is not a uri at all!!"}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if vr.IsEmpty() {
+		t.Errorf("predicate type with no URI scheme should not validate cleanly")
+	}
+}
+
+func TestExportInvalidSchemaURIIsRejected(t *testing.T) {
+	e := &Export{Subject: "foo", Type: Stream, Schema: &Schema{URI: "this is not a uri at all!!"}}
+
+	vr := CreateValidationResults()
+	e.Validate(vr)
+
+	if vr.IsEmpty() {
+		t.Errorf("schema URI with no scheme should not validate cleanly")
+	}
+}
+
+func TestExportsPredicateTypeMismatchFlagged(t *testing.T) {
+	i := &Export{Subject: "foo", Type: Stream, PredicateType: "https://example.com/predicates/order/v1"}
+	i2 := &Export{Subject: "foo", Type: Service, PredicateType: "https://example.com/predicates/invoice/v1"}
+
+	exports := &Exports{}
+	exports.Add(i, i2)
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	if len(vr.IssuesByAction(Deny)) != 1 {
+		t.Errorf("expected one predicate mismatch issue, got %d (%v)", len(vr.IssuesByAction(Deny)), vr.Issues)
+	}
+}
+
+func TestExportsPredicateTypeUnsetIsCompatible(t *testing.T) {
+	i := &Export{Subject: "foo", Type: Stream, PredicateType: "https://example.com/predicates/order/v1"}
+	i2 := &Export{Subject: "foo", Type: Service}
+
+	exports := &Exports{}
+	exports.Add(i, i2)
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	if !vr.IsEmpty() {
+		t.Errorf("an export with no declared predicate type should be compatible with anything, got %v", vr.Issues)
+	}
+}
+
+func TestNewImportSubscriptionRejectsPredicateMismatch(t *testing.T) {
+	export := &Export{Subject: "foo", Type: Stream, PredicateType: "https://example.com/predicates/order/v1"}
+	imp := &Import{Subject: "foo", Account: "A", Type: Stream, PredicateType: "https://example.com/predicates/invoice/v1"}
+
+	if _, err := NewImportSubscription(imp, export); err == nil {
+		t.Errorf("expected predicate type mismatch to be rejected")
+	}
+}
+
+func TestNewImportSubscriptionBindsOnMatch(t *testing.T) {
+	export := &Export{Subject: "foo", Type: Stream, PredicateType: "https://example.com/predicates/order/v1"}
+	imp := &Import{Subject: "foo", Account: "A", Type: Stream, PredicateType: "https://example.com/predicates/order/v1"}
+
+	sub, err := NewImportSubscription(imp, export)
+	if err != nil {
+		t.Fatalf("expected matching predicate types to bind, got error: %v", err)
+	}
+	if sub.Import != imp || sub.Export != export {
+		t.Errorf("subscription should reference the bound import and export")
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Subject is a string that represents a NATS subject, it may contain wildcards
+// such as `*` (single token) and `>` (full token, must be last).
+type Subject string
+
+const tokenSeparator = "."
+const wildcardToken = "*"
+const fullWildcardToken = ">"
+
+func (s Subject) tokenize() []string {
+	return strings.Split(string(s), tokenSeparator)
+}
+
+// HasWildCards returns true if the subject contains a `*` or `>` token.
+func (s Subject) HasWildCards() bool {
+	for _, t := range s.tokenize() {
+		if t == wildcardToken || t == fullWildcardToken {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns true if the subject is a superset of the other subject,
+// i.e. every concrete subject matched by other is also matched by s.
+func (s Subject) Contains(other Subject) bool {
+	tokens := s.tokenize()
+	otherTokens := other.tokenize()
+
+	lt := len(tokens)
+	lo := len(otherTokens)
+
+	for i := 0; i < lt; i++ {
+		token := tokens[i]
+
+		if token == fullWildcardToken {
+			// `>` must match one or more trailing tokens, so there must be at
+			// least one token left in other at this position (i < lo, not i <= lo).
+			return i < lo
+		}
+
+		if i >= lo {
+			return false
+		}
+
+		otherToken := otherTokens[i]
+		if token == wildcardToken {
+			// `*` matches exactly one token, so it cannot cover a `>` in other.
+			if otherToken == fullWildcardToken {
+				return false
+			}
+			continue
+		}
+
+		if token != otherToken {
+			return false
+		}
+	}
+
+	return lt == lo
+}
+
+// validateSubject returns an error if the subject is empty or malformed
+// (empty tokens, or a `>` that isn't the last token).
+func validateSubject(subj Subject) error {
+	if subj == "" {
+		return fmt.Errorf("subject cannot be empty")
+	}
+	tokens := subj.tokenize()
+	for i, t := range tokens {
+		if t == "" {
+			return fmt.Errorf("subject %q has an empty token", subj)
+		}
+		if t == fullWildcardToken && i != len(tokens)-1 {
+			return fmt.Errorf("subject %q has embedded full wildcard", subj)
+		}
+	}
+	return nil
+}
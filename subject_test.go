@@ -0,0 +1,38 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import "testing"
+
+func TestSubjectFullWildcardDoesNotContainBareParent(t *testing.T) {
+	// `>` must match one or more trailing tokens, so "a.>" never matches "a"
+	// on the wire and must not be considered to contain it.
+	if Subject("a.>").Contains("a") {
+		t.Errorf(`"a.>" should not contain "a" - ">" requires at least one trailing token`)
+	}
+	if Subject("$SYS.>").Contains("$SYS") {
+		t.Errorf(`"$SYS.>" should not contain "$SYS"`)
+	}
+}
+
+func TestSubjectFullWildcardContainsDeeperSubjects(t *testing.T) {
+	if !Subject("a.>").Contains("a.b") {
+		t.Errorf(`"a.>" should contain "a.b"`)
+	}
+	if !Subject("a.>").Contains("a.b.c") {
+		t.Errorf(`"a.>" should contain "a.b.c"`)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExportType defines the type of import/export.
+type ExportType int
+
+const (
+	// Unknown is used if the type is not set.
+	Unknown ExportType = iota
+	// Stream defines the type field value for a stream export/import.
+	Stream
+	// Service defines the type field value for a service export/import.
+	Service
+)
+
+// Export represents a single export from an account, so that it may be
+// imported by another account via an Import.
+type Export struct {
+	Subject       Subject        `json:"subject,omitempty"`
+	Type          ExportType     `json:"type,omitempty"`
+	TokenReq      bool           `json:"token_req,omitempty"`
+	Revocations   RevocationList `json:"revocations,omitempty"`
+	// AllowReserved lets a trusted export (e.g. a system account publishing
+	// $SYS streams) overlap a reserved namespace. The overlap is kept as a
+	// Warn issue rather than silently permitted, so it still shows up in review.
+	AllowReserved bool `json:"allow_reserved,omitempty"`
+	// PredicateType names the shape of the messages this export emits or
+	// accepts, e.g. a URI such as "https://example.com/predicates/order/v1".
+	// An empty PredicateType opts out of the check entirely.
+	PredicateType string `json:"predicate_type,omitempty"`
+	// Schema optionally backs PredicateType with a URI and/or inline JSON
+	// Schema document describing the payload in full.
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Validate checks the export for obvious problems and adds any issues found to vr.
+func (e *Export) Validate(vr *ValidationResults) {
+	if e == nil {
+		vr.AddError("null export is invalid")
+		return
+	}
+	if e.Type == Unknown {
+		vr.addCategorized(CategoryUnknownType, Deny, false, "invalid export type: %q", e.Subject)
+	}
+	if err := validateSubject(e.Subject); err != nil {
+		vr.AddError("%s", err.Error())
+	}
+	if reserved := reservedOverlap(e.Type, e.Subject); reserved != "" {
+		action := Deny
+		if e.AllowReserved {
+			action = Warn
+		}
+		vr.addCategorized(CategoryReservedSubject, action, false,
+			"%s export subject %q overlaps reserved namespace %q", exportTypeName(e.Type), e.Subject, reserved)
+	}
+	if e.PredicateType != "" {
+		if err := checkURI(e.PredicateType); err != nil {
+			vr.AddError("invalid predicate type %q: %v", e.PredicateType, err)
+		}
+	}
+	e.Schema.Validate(vr)
+}
+
+func exportTypeName(t ExportType) string {
+	switch t {
+	case Service:
+		return "service"
+	case Stream:
+		return "stream"
+	default:
+		return "unknown-type"
+	}
+}
+
+// MatchesPredicate reports whether this export's PredicateType is compatible
+// with pt. An unset PredicateType on either side matches anything, so the
+// check only engages once both sides have opted in by declaring one.
+func (e *Export) MatchesPredicate(pt string) bool {
+	if e.PredicateType == "" || pt == "" {
+		return true
+	}
+	return e.PredicateType == pt
+}
+
+// RevokeAt revokes the given public key effective at or before the given time.
+func (e *Export) RevokeAt(pubKey string, at time.Time) {
+	if e.Revocations == nil {
+		e.Revocations = RevocationList{}
+	}
+	e.Revocations.RevokeAt(pubKey, at)
+}
+
+// IsRevokedAt checks whether the public key is revoked at the given time.
+func (e *Export) IsRevokedAt(pubKey string, at time.Time) bool {
+	return e.Revocations.IsRevokedAt(pubKey, at)
+}
+
+// IsRevoked checks whether the public key is revoked right now.
+func (e *Export) IsRevoked(pubKey string) bool {
+	return e.Revocations.IsRevoked(pubKey)
+}
+
+// ClearRevocation removes any revocation for the given public key.
+func (e *Export) ClearRevocation(pubKey string) {
+	e.Revocations.ClearRevocation(pubKey)
+}
+
+// Exports is a collection of Export.
+type Exports []*Export
+
+// Add appends one or more exports to the list.
+func (e *Exports) Add(i ...*Export) {
+	*e = append(*e, i...)
+}
+
+// Validate validates every export, and additionally checks - in separate
+// passes for services, streams and Unknown-type exports - that no two
+// exports of the same Type overlap in subject space.
+func (e *Exports) Validate(vr *ValidationResults) {
+	var services, streams, unknowns []Subject
+
+	for _, exp := range *e {
+		exp.Validate(vr)
+		switch exp.Type {
+		case Service:
+			services = append(services, exp.Subject)
+		case Stream:
+			streams = append(streams, exp.Subject)
+		default:
+			// exp.Validate already added a CategoryUnknownType issue above;
+			// still check overlap so two bad exports on the same subject
+			// also get an overlap diagnostic, matching the pre-#5 behavior.
+			unknowns = append(unknowns, exp.Subject)
+		}
+	}
+
+	isContainedIn(Service, services, vr)
+	isContainedIn(Stream, streams, vr)
+	isContainedIn(Unknown, unknowns, vr)
+
+	// Same-subject exports are otherwise allowed across different kinds (see
+	// above), but if both sides declared a PredicateType they must agree -
+	// a producer and consumer disagreeing on payload semantics is a bug.
+	for i, a := range *e {
+		for _, b := range (*e)[i+1:] {
+			if a.Subject != b.Subject {
+				continue
+			}
+			if !a.MatchesPredicate(b.PredicateType) {
+				vr.addCategorized(CategoryPredicateMismatch, Deny, false,
+					"exports on subject %q have incompatible predicate types %q and %q",
+					a.Subject, a.PredicateType, b.PredicateType)
+			}
+		}
+	}
+}
+
+// isContainedIn runs overlap detection across subjects that all belong to a
+// single export kind, adding a structured, code-tagged ValidationIssue for
+// every overlapping pair so tooling can render "subject X already exports Y"
+// instead of grepping a free-form Description.
+func isContainedIn(kind ExportType, subjects []Subject, vr *ValidationResults) {
+	var code string
+	switch kind {
+	case Stream:
+		code = ErrExportOverlapStream
+	case Service:
+		code = ErrExportOverlapService
+	default:
+		code = ErrExportOverlapUnknown
+	}
+	for i, a := range subjects {
+		for _, b := range subjects[i+1:] {
+			if a.Contains(b) || b.Contains(a) {
+				vr.Add(&ValidationIssue{
+					Description:   fmt.Sprintf("%s export subject %q overlaps with %q", exportTypeName(kind), a, b),
+					Category:      CategoryOverlap,
+					Action:        Deny,
+					Kind:          kind,
+					Subject:       a,
+					ConflictsWith: b,
+					Code:          code,
+				})
+			}
+		}
+	}
+}
+
+// Import describes how an account imports a Stream or Service from another account.
+type Import struct {
+	Subject Subject    `json:"subject,omitempty"`
+	Account string     `json:"account,omitempty"`
+	Type    ExportType `json:"type,omitempty"`
+	To      Subject    `json:"to,omitempty"`
+	Token   string     `json:"token,omitempty"`
+	// PredicateType, if set here and on the Export, must match the Export's
+	// PredicateType for NewImportSubscription to bind - see ImportSubscription.
+	PredicateType string `json:"predicate_type,omitempty"`
+}
+
+// Validate checks the import for obvious problems and adds any issues found to vr.
+func (i *Import) Validate(vr *ValidationResults) {
+	if i == nil {
+		vr.AddError("null import is invalid")
+		return
+	}
+	if i.Type == Unknown {
+		vr.addCategorized(CategoryUnknownType, Deny, false, "invalid import type: %q", i.Subject)
+	}
+	if err := validateSubject(i.Subject); err != nil {
+		vr.AddError("%s", err.Error())
+	}
+	if i.Account == "" {
+		vr.AddError("import %q is missing the account it is imported from", i.Subject)
+	}
+}
+
+// Imports is a collection of Import.
+type Imports []*Import
+
+// Add appends one or more imports to the list.
+func (i *Imports) Add(imports ...*Import) {
+	*i = append(*i, imports...)
+}
+
+// Validate validates every import in the list.
+func (i *Imports) Validate(vr *ValidationResults) {
+	for _, imp := range *i {
+		imp.Validate(vr)
+	}
+}
+
+// ImportSubscription represents an Import successfully bound to the specific
+// Export that backs it, e.g. what nats-server builds when it wires up a
+// cross-account subscription.
+type ImportSubscription struct {
+	Import *Import
+	Export *Export
+}
+
+// NewImportSubscription binds imp to export, refusing to bind when the two
+// sides declare incompatible PredicateType values - this is how a producer
+// and a consumer that disagree on payload semantics get rejected instead of
+// silently wired together.
+func NewImportSubscription(imp *Import, export *Export) (*ImportSubscription, error) {
+	if imp == nil {
+		return nil, fmt.Errorf("import is nil")
+	}
+	if export == nil {
+		return nil, fmt.Errorf("import %q has no matching export", imp.Subject)
+	}
+	if imp.PredicateType != "" && export.PredicateType != "" && imp.PredicateType != export.PredicateType {
+		return nil, fmt.Errorf("import %q predicate type %q does not match export %q predicate type %q",
+			imp.Subject, imp.PredicateType, export.Subject, export.PredicateType)
+	}
+	return &ImportSubscription{Import: imp, Export: export}, nil
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import "fmt"
+
+// ValidationAction describes how a ValidationIssue should be enforced.
+type ValidationAction int
+
+const (
+	// Deny is a blocking issue: IsBlocking(true) becomes true and encode/decode
+	// callers are expected to refuse the claim.
+	Deny ValidationAction = iota
+	// Warn issues should be surfaced (see ValidationResults.Emit) but never block.
+	Warn
+	// DryRun issues are tracked so operators can see what a stricter policy
+	// would have denied, without it having any effect yet.
+	DryRun
+)
+
+// String returns the lower case name of the action, as used in logs.
+func (a ValidationAction) String() string {
+	switch a {
+	case Warn:
+		return "warn"
+	case DryRun:
+		return "dryrun"
+	default:
+		return "deny"
+	}
+}
+
+// IssueCategory classifies a ValidationIssue so a Policy can target groups of
+// issues (e.g. every overlap issue) without naming each one.
+type IssueCategory string
+
+const (
+	// CategoryGeneral is used for issues that don't have a more specific category.
+	CategoryGeneral IssueCategory = "general"
+	// CategoryOverlap is used for exports/imports whose subjects overlap.
+	CategoryOverlap IssueCategory = "overlap"
+	// CategoryUnknownType is used when an export/import Type is Unknown.
+	CategoryUnknownType IssueCategory = "unknown_type"
+	// CategoryRevocationFormat is used for malformed or legacy revocation data.
+	CategoryRevocationFormat IssueCategory = "revocation_format"
+	// CategoryReservedSubject is used when an export overlaps a reserved namespace.
+	CategoryReservedSubject IssueCategory = "reserved_subject"
+	// CategoryPredicateMismatch is used when exports on the same subject declare
+	// incompatible PredicateType values.
+	CategoryPredicateMismatch IssueCategory = "predicate_mismatch"
+)
+
+// Policy maps an IssueCategory to the ValidationAction that should be taken
+// when an issue of that category is found. Categories with no entry keep the
+// action they were created with.
+type Policy map[IssueCategory]ValidationAction
+
+// Stable error codes for ValidationIssue.Code, so tooling (nsc, nats-server)
+// can match on an identifier instead of parsing Description.
+const (
+	// ErrExportOverlapStream is the Code for two stream exports whose subjects overlap.
+	ErrExportOverlapStream = "ERR_EXPORT_OVERLAP_STREAM"
+	// ErrExportOverlapService is the Code for two service exports whose subjects overlap.
+	ErrExportOverlapService = "ERR_EXPORT_OVERLAP_SERVICE"
+	// ErrExportOverlapUnknown is the Code for two Type: Unknown exports whose
+	// subjects overlap; Unknown exports are already invalid on their own
+	// (CategoryUnknownType), but the overlap is still reported under its own
+	// code rather than being folded into ERR_EXPORT_OVERLAP_SERVICE.
+	ErrExportOverlapUnknown = "ERR_EXPORT_OVERLAP_UNKNOWN"
+)
+
+// ValidationIssue represents a problem found while validating a claim. Kind,
+// Subject, ConflictsWith and Code are only populated for issues that have a
+// natural structured representation (e.g. export overlap); callers should
+// treat a zero value in those fields as "not applicable" and fall back to Description.
+type ValidationIssue struct {
+	Description   string
+	Category      IssueCategory
+	Action        ValidationAction
+	TimeCheck     bool
+	Kind          ExportType
+	Subject       Subject
+	ConflictsWith Subject
+	Code          string
+}
+
+// Error implements the error interface.
+func (i *ValidationIssue) Error() string {
+	return i.Description
+}
+
+// ValidationResults is a collection of ValidationIssue, accumulated by the
+// various Validate methods in this package. Policy, if set, remaps the
+// action of issues by Category before IsBlocking/IssuesByAction/Emit look at them.
+type ValidationResults struct {
+	Issues []*ValidationIssue
+	Policy Policy
+}
+
+// CreateValidationResults creates an empty list of results.
+func CreateValidationResults() *ValidationResults {
+	return &ValidationResults{
+		Issues: make([]*ValidationIssue, 0, 16),
+	}
+}
+
+// Add appends an issue to the results.
+func (v *ValidationResults) Add(i *ValidationIssue) {
+	v.Issues = append(v.Issues, i)
+}
+
+func (v *ValidationResults) addCategorized(category IssueCategory, action ValidationAction, timeCheck bool, format string, args ...interface{}) {
+	v.Add(&ValidationIssue{
+		Description: fmt.Sprintf(format, args...),
+		Category:    category,
+		Action:      action,
+		TimeCheck:   timeCheck,
+	})
+}
+
+// AddError formats and adds a Deny issue with CategoryGeneral.
+func (v *ValidationResults) AddError(format string, args ...interface{}) {
+	v.addCategorized(CategoryGeneral, Deny, false, format, args...)
+}
+
+// AddTimeCheck formats and adds a Deny issue that only applies to time-based checks.
+func (v *ValidationResults) AddTimeCheck(format string, args ...interface{}) {
+	v.addCategorized(CategoryGeneral, Deny, true, format, args...)
+}
+
+// AddWarning formats and adds a Warn issue with CategoryGeneral.
+func (v *ValidationResults) AddWarning(format string, args ...interface{}) {
+	v.addCategorized(CategoryGeneral, Warn, false, format, args...)
+}
+
+// action returns the effective action for the issue once the Policy (if any) is applied.
+func (v *ValidationResults) action(i *ValidationIssue) ValidationAction {
+	if v.Policy != nil {
+		if a, ok := v.Policy[i.Category]; ok {
+			return a
+		}
+	}
+	return i.Action
+}
+
+// IsEmpty returns true if there are no issues at all.
+func (v *ValidationResults) IsEmpty() bool {
+	return len(v.Issues) == 0
+}
+
+// IsBlocking returns true if any issue's effective action is Deny. When
+// includeTimeChecks is false, issues that only represent a time-based check
+// (e.g. expiration) are ignored.
+func (v *ValidationResults) IsBlocking(includeTimeChecks bool) bool {
+	for _, i := range v.Issues {
+		if v.action(i) != Deny {
+			continue
+		}
+		if i.TimeCheck && !includeTimeChecks {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// IssuesByAction returns every issue whose effective action, after the Policy
+// is applied, matches action.
+func (v *ValidationResults) IssuesByAction(action ValidationAction) []*ValidationIssue {
+	var out []*ValidationIssue
+	for _, i := range v.Issues {
+		if v.action(i) == action {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Filter returns every issue whose Code matches, for tooling that wants to
+// key off a stable identifier (e.g. ErrExportOverlapStream) instead of
+// parsing Description.
+func (v *ValidationResults) Filter(code string) []*ValidationIssue {
+	var out []*ValidationIssue
+	for _, i := range v.Issues {
+		if i.Code == code {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Logger is the minimal logging interface Emit writes to; nats-server's
+// logger satisfies it.
+type Logger interface {
+	Noticef(format string, v ...interface{})
+}
+
+// Emit logs every issue whose effective action is Warn. It never fails
+// encoding/decoding on its own - Deny issues are for the caller to act on via
+// IsBlocking/IssuesByAction.
+func (v *ValidationResults) Emit(logger Logger) {
+	for _, i := range v.IssuesByAction(Warn) {
+		logger.Noticef("%s", i.Description)
+	}
+}
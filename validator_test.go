@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import "testing"
+
+type testLogger struct {
+	notices []string
+}
+
+func (l *testLogger) Noticef(format string, v ...interface{}) {
+	l.notices = append(l.notices, format)
+}
+
+func TestDefaultPolicyOverlapIsBlocking(t *testing.T) {
+	i := &Export{Subject: "bar.foo", Type: Stream}
+	i2 := &Export{Subject: "bar.*", Type: Stream}
+
+	exports := &Exports{}
+	exports.Add(i, i2)
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	if !vr.IsBlocking(true) {
+		t.Errorf("overlap should be blocking under the default policy")
+	}
+	if len(vr.IssuesByAction(Deny)) != 1 {
+		t.Errorf("expected one Deny issue, got %d", len(vr.IssuesByAction(Deny)))
+	}
+}
+
+func TestPolicyDowngradesOverlapToWarn(t *testing.T) {
+	i := &Export{Subject: "bar.foo", Type: Stream}
+	i2 := &Export{Subject: "bar.*", Type: Stream}
+
+	exports := &Exports{}
+	exports.Add(i, i2)
+
+	vr := CreateValidationResults()
+	vr.Policy = Policy{CategoryOverlap: Warn}
+	exports.Validate(vr)
+
+	if vr.IsBlocking(true) {
+		t.Errorf("overlap mapped to Warn should not be blocking")
+	}
+
+	warnings := vr.IssuesByAction(Warn)
+	if len(warnings) != 1 {
+		t.Errorf("expected one Warn issue, got %d", len(warnings))
+	}
+
+	logger := &testLogger{}
+	vr.Emit(logger)
+	if len(logger.notices) != 1 {
+		t.Errorf("expected Emit to log the one Warn issue, got %d", len(logger.notices))
+	}
+}
+
+func TestPolicyDryRunIsNotBlockingOrLogged(t *testing.T) {
+	e := &Export{Subject: "foo", Type: Unknown}
+
+	vr := CreateValidationResults()
+	vr.Policy = Policy{CategoryUnknownType: DryRun}
+	e.Validate(vr)
+
+	if vr.IsBlocking(true) {
+		t.Errorf("dry run issues should never block")
+	}
+	if len(vr.IssuesByAction(DryRun)) != 1 {
+		t.Errorf("expected one DryRun issue, got %d", len(vr.IssuesByAction(DryRun)))
+	}
+
+	logger := &testLogger{}
+	vr.Emit(logger)
+	if len(logger.notices) != 0 {
+		t.Errorf("Emit should only log Warn issues, not DryRun")
+	}
+}